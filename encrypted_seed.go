@@ -0,0 +1,160 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrInvalidArmor says the encrypted seed blob is not a well formed
+// "NKEY ENCRYPTED SEED" armor.
+var ErrInvalidArmor = errors.New("nkeys: invalid encrypted seed armor")
+
+// ErrDecryptFailed says the passphrase did not open the encrypted seed
+// (wrong passphrase, or the blob was tampered with). Open also returns it
+// when a sealed payload or SealMulti envelope fails to authenticate.
+var ErrDecryptFailed = errors.New("nkeys: could not decrypt seed, wrong passphrase?")
+
+const (
+	armorHeader = "-----BEGIN NKEY ENCRYPTED SEED-----"
+	armorFooter = "-----END NKEY ENCRYPTED SEED-----"
+
+	scryptSaltLen = 16
+	scryptN       = 32768
+	scryptR       = 8
+	scryptP       = 1
+	scryptKeyLen  = 32
+)
+
+// EncryptSeed encrypts an encoded nkeys seed with passphrase, producing an
+// ASCII-armored blob suitable for storing on disk. The key encrypting key
+// is derived with scrypt (N=32768, r=8, p=1) from a random 16-byte salt,
+// and the seed is sealed with NaCl secretbox under a random 24-byte nonce.
+// The on-disk format, before armoring, is salt || nonce || ciphertext.
+func EncryptSeed(seed []byte, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, scryptSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(key)
+	var keyArr [32]byte
+	copy(keyArr[:], key)
+	defer wipe(keyArr[:])
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, 0, scryptSaltLen+24+len(seed)+secretbox.Overhead)
+	blob = append(blob, salt...)
+	blob = append(blob, nonce[:]...)
+	blob = secretbox.Seal(blob, seed, &nonce, &keyArr)
+
+	return armor(blob), nil
+}
+
+// DecryptSeed reverses EncryptSeed, verifying the secretbox MAC with the
+// key derived from passphrase before returning the original encoded seed.
+func DecryptSeed(armored []byte, passphrase []byte) ([]byte, error) {
+	blob, err := dearmor(armored)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) < scryptSaltLen+24+secretbox.Overhead {
+		return nil, ErrInvalidArmor
+	}
+	salt := blob[:scryptSaltLen]
+	var nonce [24]byte
+	copy(nonce[:], blob[scryptSaltLen:scryptSaltLen+24])
+	ciphertext := blob[scryptSaltLen+24:]
+
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(key)
+	var keyArr [32]byte
+	copy(keyArr[:], key)
+	defer wipe(keyArr[:])
+
+	seed, ok := secretbox.Open(nil, ciphertext, &nonce, &keyArr)
+	if !ok {
+		return nil, ErrDecryptFailed
+	}
+	return seed, nil
+}
+
+// armor wraps raw bytes in base64 inside the NKEY ENCRYPTED SEED markers,
+// 64 characters per line.
+func armor(raw []byte) []byte {
+	enc := base64.StdEncoding.EncodeToString(raw)
+	var buf bytes.Buffer
+	buf.WriteString(armorHeader)
+	buf.WriteByte('\n')
+	for len(enc) > 64 {
+		buf.WriteString(enc[:64])
+		buf.WriteByte('\n')
+		enc = enc[64:]
+	}
+	if len(enc) > 0 {
+		buf.WriteString(enc)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(armorFooter)
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// dearmor strips the NKEY ENCRYPTED SEED markers and base64-decodes the
+// body between them.
+func dearmor(data []byte) ([]byte, error) {
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if len(lines) < 3 {
+		return nil, ErrInvalidArmor
+	}
+	if string(bytes.TrimSpace(lines[0])) != armorHeader {
+		return nil, ErrInvalidArmor
+	}
+	if string(bytes.TrimSpace(lines[len(lines)-1])) != armorFooter {
+		return nil, ErrInvalidArmor
+	}
+	var b64 bytes.Buffer
+	for _, line := range lines[1 : len(lines)-1] {
+		b64.Write(bytes.TrimSpace(line))
+	}
+	raw, err := base64.StdEncoding.DecodeString(b64.String())
+	if err != nil {
+		return nil, ErrInvalidArmor
+	}
+	return raw, nil
+}
+
+// wipe zeroes out intermediate key material.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
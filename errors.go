@@ -0,0 +1,45 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import "errors"
+
+var (
+	// ErrInvalidPrefixByte says the prefix byte of an encoded key is invalid.
+	ErrInvalidPrefixByte = errors.New("nkeys: invalid prefix byte")
+
+	// ErrInvalidKey says the key could not be decoded.
+	ErrInvalidKey = errors.New("nkeys: invalid key")
+
+	// ErrInvalidSeedLen says the seed given to FromRawSeed was not the right length.
+	ErrInvalidSeedLen = errors.New("nkeys: invalid seed length")
+
+	// ErrInvalidSeed says the decoded seed is not usable.
+	ErrInvalidSeed = errors.New("nkeys: invalid seed")
+
+	// ErrInvalidEncoding says the base32 encoding of a key is malformed.
+	ErrInvalidEncoding = errors.New("nkeys: invalid encoded key")
+
+	// ErrInvalidSignature says a signature did not verify against the given input.
+	ErrInvalidSignature = errors.New("nkeys: signature verification failed")
+
+	// ErrCannotSign says the KeyPair does not hold a private key and cannot sign.
+	ErrCannotSign = errors.New("nkeys: cannot sign, no private key available")
+
+	// ErrPublicKeyOnly says an operation requiring a seed was attempted on a public-key-only KeyPair.
+	ErrPublicKeyOnly = errors.New("nkeys: no seed is available with a public key only object")
+
+	// ErrIncompatibleKey says the KeyPair is not usable for the attempted operation.
+	ErrIncompatibleKey = errors.New("nkeys: incompatible key")
+)
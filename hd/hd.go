@@ -0,0 +1,140 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hd implements BIP32/BIP44-style hierarchical deterministic
+// derivation of nkeys from a single master seed, so an operator can
+// provision many role-specific keys (e.g. "m/44'/nats'/0'/user/3") without
+// storing each seed separately.
+package hd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/nkeys"
+)
+
+// ErrInvalidPath says the derivation path is malformed or names no key
+// type (user, account, server, cluster, operator or curve).
+var ErrInvalidPath = errors.New("hd: invalid derivation path")
+
+// masterSeedKey is the HMAC key used to derive the master (key, chain code)
+// pair from the raw master seed, following the BIP32 convention of a fixed
+// domain-separated constant.
+const masterSeedKey = "nkeys seed"
+
+// Derive walks path (e.g. "m/44'/nats'/0'/user/3") from masterSeed and
+// returns the resulting child KeyPair. Numeric path segments are hardened
+// child indices (an optional trailing "'" marks them explicitly hardened,
+// but every step in this scheme is hardened); non-numeric segments are
+// hardened-derived from the SHA-256 of their name, and those that name a
+// key type (user, account, server, cluster, operator, curve) also select
+// the prefix of the final key.
+func Derive(masterSeed []byte, path string) (nkeys.KeyPair, error) {
+	if len(masterSeed) != 32 {
+		return nil, nkeys.ErrInvalidSeedLen
+	}
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || segments[0] != "m" {
+		return nil, ErrInvalidPath
+	}
+
+	key, chainCode := masterKey(masterSeed)
+
+	var prefix nkeys.PrefixByte
+	var havePrefix bool
+
+	for _, seg := range segments[1:] {
+		if seg == "" {
+			return nil, ErrInvalidPath
+		}
+		name := strings.TrimSuffix(seg, "'")
+		if pre, ok := prefixForSegment(name); ok {
+			prefix = pre
+			havePrefix = true
+		}
+		// Every segment, including a key-type name, takes a derivation
+		// step: two different key types at the same numeric index must
+		// not share the same underlying private scalar.
+		key, chainCode = step(chainCode, key, indexForSegment(name))
+	}
+
+	if !havePrefix {
+		return nil, ErrInvalidPath
+	}
+	return nkeys.FromRawSeed(prefix, key[:])
+}
+
+// masterKey computes the initial (key, chain code) pair from the raw master
+// seed via HMAC-SHA512("nkeys seed", masterSeed).
+func masterKey(seed []byte) (key, chainCode [32]byte) {
+	mac := hmac.New(sha512.New, []byte(masterSeedKey))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	copy(key[:], sum[:32])
+	copy(chainCode[:], sum[32:])
+	return key, chainCode
+}
+
+// step advances one hardened derivation: HMAC-SHA512(chainCode, 0x00 ||
+// key || index) split into the next (key, chain code) pair.
+func step(chainCode, key [32]byte, index uint32) (newKey, newChainCode [32]byte) {
+	var data [1 + 32 + 4]byte
+	data[0] = 0x00
+	copy(data[1:33], key[:])
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, chainCode[:])
+	mac.Write(data[:])
+	sum := mac.Sum(nil)
+	copy(newKey[:], sum[:32])
+	copy(newChainCode[:], sum[32:])
+	return newKey, newChainCode
+}
+
+// indexForSegment turns a path segment into a derivation index: digits
+// parse directly, anything else is hashed so arbitrary labels (like
+// "nats") still derive deterministically.
+func indexForSegment(name string) uint32 {
+	if n, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return uint32(n) | 0x80000000
+	}
+	sum := sha256.Sum256([]byte(name))
+	return binary.BigEndian.Uint32(sum[:4]) | 0x80000000
+}
+
+// prefixForSegment reports whether name is one of the recognized nkeys
+// types, and if so which PrefixByte it selects for the derived key.
+func prefixForSegment(name string) (nkeys.PrefixByte, bool) {
+	switch strings.ToLower(name) {
+	case "user":
+		return nkeys.PrefixByteUser, true
+	case "account":
+		return nkeys.PrefixByteAccount, true
+	case "server":
+		return nkeys.PrefixByteServer, true
+	case "cluster":
+		return nkeys.PrefixByteCluster, true
+	case "operator":
+		return nkeys.PrefixByteOperator, true
+	case "curve":
+		return nkeys.PrefixByteCurve, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import (
+	"crypto/ed25519"
+)
+
+// kp is a KeyPair backed by an encoded seed, so it is able to both sign and
+// verify.
+type kp struct {
+	seed []byte
+}
+
+func (pair *kp) keys() (PrefixByte, ed25519.PrivateKey, error) {
+	prefix, raw, err := decodeSeed(string(pair.seed))
+	if err != nil {
+		return 0, nil, err
+	}
+	return prefix, ed25519.NewKeyFromSeed(raw), nil
+}
+
+// Seed returns the encoded seed.
+func (pair *kp) Seed() ([]byte, error) {
+	return pair.seed, nil
+}
+
+// PublicKey returns the encoded public key associated with this KeyPair.
+func (pair *kp) PublicKey() (string, error) {
+	prefix, priv, err := pair.keys()
+	if err != nil {
+		return "", err
+	}
+	pub, ok := priv.Public().(ed25519.PublicKey)
+	if !ok {
+		return "", ErrInvalidKey
+	}
+	return encode(prefix, pub)
+}
+
+// PrivateKey returns the raw ed25519 private key bytes.
+func (pair *kp) PrivateKey() ([]byte, error) {
+	_, priv, err := pair.keys()
+	if err != nil {
+		return nil, err
+	}
+	cp := make([]byte, len(priv))
+	copy(cp, priv)
+	return cp, nil
+}
+
+// Sign signs the input with the private key derived from the seed.
+func (pair *kp) Sign(input []byte) ([]byte, error) {
+	_, priv, err := pair.keys()
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(priv, input), nil
+}
+
+// Verify verifies the signature against the input using the public half of
+// this KeyPair's seed.
+func (pair *kp) Verify(input []byte, sig []byte) error {
+	_, priv, err := pair.keys()
+	if err != nil {
+		return err
+	}
+	pub, ok := priv.Public().(ed25519.PublicKey)
+	if !ok {
+		return ErrInvalidKey
+	}
+	if !ed25519.Verify(pub, input, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Seal is not available for an ed25519 signing KeyPair.
+func (pair *kp) Seal(input []byte, recipientPub string) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// Open is not available for an ed25519 signing KeyPair.
+func (pair *kp) Open(input []byte, senderPub string) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// SealMulti is not available for an ed25519 signing KeyPair.
+func (pair *kp) SealMulti(plaintext []byte, recipients []string) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// Wipe overwrites the in-memory copy of the encoded seed with 'x' bytes.
+func (pair *kp) Wipe() {
+	for i := range pair.seed {
+		pair.seed[i] = 'x'
+	}
+}
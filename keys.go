@@ -0,0 +1,206 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import (
+	"encoding/base32"
+)
+
+// PrefixByte is a lead byte representing the type of key, encoded into the
+// first character(s) of the base32 string form.
+type PrefixByte byte
+
+const (
+	// PrefixByteSeed is the prefix byte used for encoded seeds.
+	PrefixByteSeed PrefixByte = 18 << 3 // S
+
+	// PrefixByteOperator is the prefix byte used for encoded operator keys.
+	PrefixByteOperator PrefixByte = 14 << 3 // O
+
+	// PrefixByteServer is the prefix byte used for encoded server keys.
+	PrefixByteServer PrefixByte = 13 << 3 // N
+
+	// PrefixByteCluster is the prefix byte used for encoded cluster keys.
+	PrefixByteCluster PrefixByte = 2 << 3 // C
+
+	// PrefixByteAccount is the prefix byte used for encoded account keys.
+	PrefixByteAccount PrefixByte = 0 << 3 // A
+
+	// PrefixByteUser is the prefix byte used for encoded user keys.
+	PrefixByteUser PrefixByte = 20 << 3 // U
+
+	// PrefixByteCurve is the prefix byte used for encoded X25519 (curve) keys.
+	PrefixByteCurve PrefixByte = 23 << 3 // X
+
+	// PrefixByteUnknown is used to indicate that we could not infer the type.
+	PrefixByteUnknown PrefixByte = 25 << 3 // Z, unused combination
+)
+
+// b32Enc is the unpadded base32 encoding used for all nkeys strings.
+var b32Enc = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// crc16Table is the CCITT CRC-16 table used to checksum encoded keys.
+var crc16Table = func() [256]uint16 {
+	const poly = 0x1021
+	var t [256]uint16
+	for i := 0; i < 256; i++ {
+		crc := uint16(i) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = (crc << 1) ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}()
+
+// crc16 computes the CRC-16 checksum appended to every encoded key.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc = (crc << 8) ^ crc16Table[byte(crc>>8)^b]
+	}
+	return crc
+}
+
+// encode encodes a raw payload with the given prefix and a trailing CRC-16,
+// producing the base32 string users see (e.g. "ACCT...", "UAB...").
+func encode(prefix PrefixByte, payload []byte) (string, error) {
+	if !isValidPublicPrefix(prefix) {
+		return "", ErrInvalidPrefixByte
+	}
+	raw := make([]byte, 1+len(payload)+2)
+	raw[0] = byte(prefix)
+	copy(raw[1:], payload)
+	crc := crc16(raw[:1+len(payload)])
+	raw[len(raw)-2] = byte(crc)
+	raw[len(raw)-1] = byte(crc >> 8)
+	return b32Enc.EncodeToString(raw), nil
+}
+
+// decode reverses encode, validating the checksum and returning the prefix
+// byte and the raw payload.
+func decode(s string) (PrefixByte, []byte, error) {
+	raw, err := b32Enc.DecodeString(s)
+	if err != nil {
+		return 0, nil, ErrInvalidEncoding
+	}
+	if len(raw) < 4 {
+		return 0, nil, ErrInvalidEncoding
+	}
+	body := raw[:len(raw)-2]
+	wantCRC := uint16(raw[len(raw)-2]) | uint16(raw[len(raw)-1])<<8
+	if crc16(body) != wantCRC {
+		return 0, nil, ErrInvalidEncoding
+	}
+	return PrefixByte(body[0]), body[1:], nil
+}
+
+// encodeSeed encodes a raw seed together with the prefix byte of the key
+// type it will produce (e.g. user, account), so the seed string alone
+// carries enough information to regenerate the proper public key.
+func encodeSeed(public PrefixByte, src []byte) (string, error) {
+	if !isValidPublicPrefix(public) {
+		return "", ErrInvalidPrefixByte
+	}
+	// public is a 5-bit "digit" left-shifted into bits 3-7 of the byte
+	// (digit = byte(public)>>3). Split that 5-bit digit 3 bits into b1's
+	// low bits (alongside PrefixByteSeed's own 5 bits) and 2 bits into
+	// b2's high bits.
+	digit := byte(public) >> 3
+	b1 := byte(PrefixByteSeed) | (digit >> 2)
+	b2 := (digit & 3) << 6
+
+	raw := make([]byte, 2+len(src)+2)
+	raw[0] = b1
+	raw[1] = b2
+	copy(raw[2:], src)
+	crc := crc16(raw[:2+len(src)])
+	raw[len(raw)-2] = byte(crc)
+	raw[len(raw)-1] = byte(crc >> 8)
+	return b32Enc.EncodeToString(raw), nil
+}
+
+// decodeSeed reverses encodeSeed, returning the public prefix byte and the
+// raw seed bytes.
+func decodeSeed(s string) (PrefixByte, []byte, error) {
+	raw, err := b32Enc.DecodeString(s)
+	if err != nil {
+		return 0, nil, ErrInvalidEncoding
+	}
+	if len(raw) < 4 {
+		return 0, nil, ErrInvalidEncoding
+	}
+	body := raw[:len(raw)-2]
+	wantCRC := uint16(raw[len(raw)-2]) | uint16(raw[len(raw)-1])<<8
+	if crc16(body) != wantCRC {
+		return 0, nil, ErrInvalidEncoding
+	}
+	b1 := body[0] & 248
+	if PrefixByte(b1) != PrefixByteSeed {
+		return 0, nil, ErrInvalidSeed
+	}
+	digit := (body[0]&7)<<2 | body[1]>>6
+	public := PrefixByte(digit << 3)
+	return public, body[2:], nil
+}
+
+// isValidPublicPrefix reports whether b is one of the known public key
+// (non-seed) prefix bytes.
+func isValidPublicPrefix(b PrefixByte) bool {
+	switch b {
+	case PrefixByteOperator, PrefixByteServer, PrefixByteCluster,
+		PrefixByteAccount, PrefixByteUser, PrefixByteCurve:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidEncoding reports whether the given bytes look like a well formed
+// base32 string with a matching CRC-16 checksum; the prefix byte is not
+// checked.
+func IsValidEncoding(raw []byte) bool {
+	if len(raw) < 4 {
+		return false
+	}
+	decoded, err := b32Enc.DecodeString(string(raw))
+	if err != nil || len(decoded) < 4 {
+		return false
+	}
+	body := decoded[:len(decoded)-2]
+	wantCRC := uint16(decoded[len(decoded)-2]) | uint16(decoded[len(decoded)-1])<<8
+	return crc16(body) == wantCRC
+}
+
+// DecodeSeed decodes an encoded seed string, returning the public key
+// PrefixByte it was created for and the raw 32-byte seed.
+func DecodeSeed(seed []byte) (PrefixByte, []byte, error) {
+	return decodeSeed(string(seed))
+}
+
+// Prefix inspects an encoded key string and returns the PrefixByte
+// identifying its type, or PrefixByteUnknown if it cannot be determined.
+func Prefix(s string) PrefixByte {
+	if prefix, _, err := decode(s); err == nil && isValidPublicPrefix(prefix) {
+		return prefix
+	}
+	if prefix, _, err := decodeSeed(s); err == nil {
+		return prefix
+	}
+	return PrefixByteUnknown
+}
@@ -0,0 +1,57 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import "testing"
+
+func TestSeedRoundTripAllPrefixes(t *testing.T) {
+	prefixes := []PrefixByte{
+		PrefixByteOperator,
+		PrefixByteServer,
+		PrefixByteCluster,
+		PrefixByteAccount,
+		PrefixByteUser,
+		PrefixByteCurve,
+	}
+
+	rawSeed := make([]byte, 32)
+	for i := range rawSeed {
+		rawSeed[i] = byte(i)
+	}
+
+	for _, prefix := range prefixes {
+		kp, err := FromRawSeed(prefix, rawSeed)
+		if err != nil {
+			t.Fatalf("FromRawSeed(%v): %v", prefix, err)
+		}
+		if _, err := kp.PublicKey(); err != nil {
+			t.Fatalf("PublicKey() for prefix %v: %v", prefix, err)
+		}
+
+		seed, err := kp.Seed()
+		if err != nil {
+			t.Fatalf("Seed() for prefix %v: %v", prefix, err)
+		}
+		gotPrefix, gotRaw, err := DecodeSeed(seed)
+		if err != nil {
+			t.Fatalf("DecodeSeed() for prefix %v: %v", prefix, err)
+		}
+		if gotPrefix != prefix {
+			t.Fatalf("DecodeSeed() prefix = %v, want %v", gotPrefix, prefix)
+		}
+		if string(gotRaw) != string(rawSeed) {
+			t.Fatalf("DecodeSeed() raw seed mismatch for prefix %v", prefix)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidMnemonic says the mnemonic phrase failed checksum validation,
+// was not 24 words long, or held a word not in the wordlist.
+var ErrInvalidMnemonic = errors.New("nkeys: invalid mnemonic")
+
+// bip39Index maps each wordlist entry back to its 11-bit index, built once
+// at init time.
+var bip39Index = func() map[string]int {
+	m := make(map[string]int, len(bip39EnglishWords))
+	for i, w := range bip39EnglishWords {
+		m[w] = i
+	}
+	return m
+}()
+
+// SeedToMnemonic converts a 32-byte raw seed into its 24-word BIP-0039
+// English mnemonic. The entropy's SHA-256 checksum's first 8 bits are
+// appended to the 256 bits of entropy to form 264 bits, which are then
+// split into 24 groups of 11 bits that index into the wordlist.
+func SeedToMnemonic(rawSeed []byte) (string, error) {
+	if len(rawSeed) != 32 {
+		return "", ErrInvalidSeedLen
+	}
+	sum := sha256.Sum256(rawSeed)
+	bits := append(append([]byte{}, rawSeed...), sum[0])
+
+	words := make([]string, 24)
+	for i := 0; i < 24; i++ {
+		words[i] = bip39EnglishWords[take11Bits(bits, i*11)]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// SeedFromMnemonic reverses SeedToMnemonic, validating the embedded checksum
+// before returning the original 32-byte raw seed.
+func SeedFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) != 24 {
+		return nil, ErrInvalidMnemonic
+	}
+
+	bits := make([]byte, 33)
+	for i, w := range words {
+		idx, ok := bip39Index[w]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		put11Bits(bits, i*11, idx)
+	}
+
+	rawSeed := bits[:32]
+	sum := sha256.Sum256(rawSeed)
+	if bits[32] != sum[0] {
+		return nil, ErrInvalidMnemonic
+	}
+	return rawSeed, nil
+}
+
+// take11Bits reads an 11-bit big-endian value starting at the given bit
+// offset out of a byte slice.
+func take11Bits(data []byte, bitOffset int) int {
+	var v int
+	for i := 0; i < 11; i++ {
+		v <<= 1
+		byteIdx := (bitOffset + i) / 8
+		bitIdx := 7 - (bitOffset+i)%8
+		v |= int((data[byteIdx] >> bitIdx) & 1)
+	}
+	return v
+}
+
+// put11Bits writes an 11-bit big-endian value into data starting at the
+// given bit offset.
+func put11Bits(data []byte, bitOffset, value int) {
+	for i := 0; i < 11; i++ {
+		bit := byte((value >> (10 - i)) & 1)
+		byteIdx := (bitOffset + i) / 8
+		bitIdx := 7 - (bitOffset+i)%8
+		if bit == 1 {
+			data[byteIdx] |= 1 << bitIdx
+		} else {
+			data[byteIdx] &^= 1 << bitIdx
+		}
+	}
+}
@@ -16,6 +16,7 @@ package main
 import (
 	"bytes"
 	"crypto/rand"
+	"crypto/sha512"
 	"encoding/base32"
 	"encoding/base64"
 	"flag"
@@ -27,13 +28,14 @@ import (
 	"strings"
 
 	"github.com/nats-io/nkeys"
+	"github.com/nats-io/nkeys/hd"
 )
 
 // this will be set during compilation when a release is made on tools
 var Version string
 
 func usage() {
-	log.Fatalf("Usage: nk [-v] [-gen type] [-sign content] [-verify content] [-inkey key] [-pubin publickey] [-sig signature] [-pubout] [-e entropy] [-pre vanity]\n")
+	log.Fatalf("Usage: nk [-v] [-gen type] [-sign file] [-verify file] [-in file] [-inkey key] [-pubin publickey] [-sig signature-file] [-pubout] [-e entropy] [-pre vanity] [-seal file -recipient xpub] [-open file] [-mnemonic] [-from-mnemonic phrase] [-derive path] [-encrypt -passphrase file] [-decrypt file -passphrase file] [-seal-multi file -recipient xpub,xpub,...]\n")
 }
 
 func main() {
@@ -42,9 +44,11 @@ func main() {
 	var pub = flag.String("pubin", "", "Public key ")
 
 	var signContent = flag.String("sign", "", "Sign <file> with -inkey <key>")
-	var sig = flag.String("sig", "", "Signature content")
+	var sig = flag.String("sig", "", "File holding the base64 signature, for -verify")
 
-	var verifyContent = flag.String("verify", "", "Verify content with -inkey <key> or -pubin <public> and -sig <file>")
+	var verifyContent = flag.String("verify", "", "Verify <file> with -inkey <key> or -pubin <public> and -sig <file>")
+
+	var inFile = flag.String("in", "", "Stream this file (or - for stdin) through a SHA-512 pre-hash for -sign/-verify, instead of loading it into memory")
 
 	var keyType = flag.String("gen", "", "Generate key for <type>, e.g. nk -gen user")
 	var pubout = flag.Bool("pubout", false, "Output public key")
@@ -53,6 +57,20 @@ func main() {
 	var vanPre = flag.String("pre", "", "Attempt to generate public key given prefix, e.g. nk -gen user -pre derek")
 	var vanMax = flag.Int("maxpre", 10000000, "Maximum attempts at generating the correct key prefix")
 
+	var sealFile = flag.String("seal", "", "Seal <file> with -inkey <xseed> for -recipient <xpub>")
+	var recipient = flag.String("recipient", "", "Recipient xkey public key for -seal, or a comma-separated list for -seal-multi")
+	var openFile = flag.String("open", "", "Open <file> with -inkey <xseed>, sealed by -pubin <xpub>")
+	var sealMultiFile = flag.String("seal-multi", "", "Seal <file> with -inkey <xseed> for the comma-separated -recipient xpubs")
+
+	var toMnemonic = flag.Bool("mnemonic", false, "Show the BIP39 mnemonic for the seed given via -inkey")
+	var fromMnemonic = flag.String("from-mnemonic", "", "Reconstruct a seed of type -gen <type> from a BIP39 mnemonic phrase")
+
+	var derivePath = flag.String("derive", "", "Derive a child key from the master seed given via -inkey along <path>, e.g. m/44'/nats'/0'/user/3")
+
+	var encrypt = flag.Bool("encrypt", false, "Encrypt the seed given via -inkey <key> with -passphrase <file>")
+	var decryptFile = flag.String("decrypt", "", "Decrypt an armored seed <file> with -passphrase <file>")
+	var passphrase = flag.String("passphrase", "", "File holding the passphrase for -encrypt/-decrypt")
+
 	log.SetFlags(0)
 	log.SetOutput(os.Stdout)
 
@@ -63,6 +81,36 @@ func main() {
 		fmt.Printf("nk version %s\n", Version)
 	}
 
+	// Reconstruct a seed from a BIP39 mnemonic.
+	if *fromMnemonic != "" {
+		seedFromMnemonic(*fromMnemonic, *keyType)
+		return
+	}
+
+	// Show the BIP39 mnemonic for a seed.
+	if *toMnemonic {
+		mnemonicFromSeed(*key)
+		return
+	}
+
+	// Derive a child key from a master seed along an HD path.
+	if *derivePath != "" {
+		derive(*key, *derivePath)
+		return
+	}
+
+	// Encrypt a seed with a passphrase.
+	if *encrypt {
+		encryptSeed(*key, *passphrase)
+		return
+	}
+
+	// Decrypt a seed with a passphrase.
+	if *decryptFile != "" {
+		decryptSeed(*decryptFile, *passphrase)
+		return
+	}
+
 	// Create Key
 	if *keyType != "" {
 		var kp nkeys.KeyPair
@@ -90,13 +138,31 @@ func main() {
 
 	// Sign
 	if *signContent != "" {
-		sign(*signContent, *key)
+		sign(*signContent, *key, *inFile)
 		return
 	}
 
 	// Verfify
 	if *verifyContent != "" {
-		verify(*verifyContent, *key, *pub, *sig)
+		verify(*verifyContent, *key, *pub, *sig, *inFile)
+		return
+	}
+
+	// Seal content to a recipient's xkey.
+	if *sealFile != "" {
+		seal(*sealFile, *key, *recipient)
+		return
+	}
+
+	// Open content sealed by a sender's xkey.
+	if *openFile != "" {
+		open(*openFile, *key, *pub)
+		return
+	}
+
+	// Seal content to multiple recipients' xkeys.
+	if *sealMultiFile != "" {
+		sealMulti(*sealMultiFile, *key, *recipient)
 		return
 	}
 
@@ -120,7 +186,7 @@ func printPublicFromSeed(keyFile string) {
 	log.Printf("%s", pub)
 }
 
-func sign(name, key string) {
+func sign(name, key, inFile string) {
 	if key == "" {
 		log.Fatalf("Sign requires a seed/private key via -inkey <file>")
 	}
@@ -130,7 +196,10 @@ func sign(name, key string) {
 		log.Fatal(err)
 	}
 
-	content := []byte(name)
+	content, err := signableContent(name, inFile)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	sigraw, err := kp.Sign(content)
 	if err != nil {
@@ -139,38 +208,34 @@ func sign(name, key string) {
 	log.Printf("%s", base64.StdEncoding.EncodeToString(sigraw))
 }
 
-func verify(fname, keyFile, pubFile, sigFile string) {
+func verify(fname, keyFile, pubFile, sigFile, inFile string) {
 	if keyFile == "" && pubFile == "" {
 		log.Fatalf("Verify requires a seed key via -inkey or a public key via -pubin")
 	}
 	if sigFile == "" {
-		log.Fatalf("Verify requires a signature via -sigfile")
+		log.Fatalf("Verify requires a signature file via -sig")
 	}
 	var err error
 	var kp nkeys.KeyPair
 	if keyFile != "" {
-		var seed []byte = []byte(keyFile)
-
-		if err != nil {
-			log.Fatal(err)
-		}
-		kp, err = nkeys.FromSeed(seed)
+		kp, err = nkeys.FromSeed(readKey(keyFile))
 	} else {
-		// Public Key
-		var public []byte = []byte(pubFile)
-
-		if err != nil {
-			log.Fatal(err)
-		}
-		kp, err = nkeys.FromPublicKey(string(public))
+		kp, err = nkeys.FromPublicKey(string(readKey(pubFile)))
 	}
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	content := []byte(fname)
+	content, err := signableContent(fname, inFile)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	sig, err := base64.StdEncoding.DecodeString(sigFile)
+	sigB64, err := os.ReadFile(sigFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigB64)))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -180,6 +245,205 @@ func verify(fname, keyFile, pubFile, sigFile string) {
 	log.Printf("Verified OK")
 }
 
+// signableContent returns the bytes that should be passed to Sign/Verify
+// for the given -sign/-verify <file> argument. When inFile is set it is
+// streamed (stdin if "-") through a SHA-512 pre-hash so arbitrarily large
+// payloads never have to be loaded into memory; otherwise fname is read in
+// full and signed/verified directly.
+func signableContent(fname, inFile string) ([]byte, error) {
+	if inFile != "" {
+		var r io.Reader
+		if inFile == "-" {
+			r = os.Stdin
+		} else {
+			f, err := os.Open(inFile)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+		h := sha512.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return nil, err
+		}
+		return h.Sum(nil), nil
+	}
+	return os.ReadFile(fname)
+}
+
+func seal(fname, key, recipient string) {
+	if key == "" {
+		log.Fatalf("Seal requires an xkey seed via -inkey <key>")
+	}
+	if recipient == "" {
+		log.Fatalf("Seal requires a recipient xkey public key via -recipient <xpub>")
+	}
+	seed := readKey(key)
+	kp, err := nkeys.FromSeed(seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	content, err := os.ReadFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sealed, err := kp.Seal(content, recipient)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s", base64.StdEncoding.EncodeToString(sealed))
+}
+
+func open(fname, key, sender string) {
+	if key == "" {
+		log.Fatalf("Open requires an xkey seed via -inkey <key>")
+	}
+	if sender == "" {
+		log.Fatalf("Open requires the sender's xkey public key via -pubin <xpub>")
+	}
+	seed := readKey(key)
+	kp, err := nkeys.FromSeed(seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	encoded, err := os.ReadFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	content, err := kp.Open(sealed, sender)
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(content)
+}
+
+func mnemonicFromSeed(key string) {
+	if key == "" {
+		log.Fatalf("Mnemonic requires a seed via -inkey <key>")
+	}
+	seed := readKey(key)
+	_, rawSeed, err := nkeys.DecodeSeed(seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mnemonic, err := nkeys.SeedToMnemonic(rawSeed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s", mnemonic)
+}
+
+func seedFromMnemonic(mnemonic, keyType string) {
+	if keyType == "" {
+		log.Fatalf("-from-mnemonic requires -gen <type> to know which key type to reconstruct")
+	}
+	rawSeed, err := nkeys.SeedFromMnemonic(mnemonic)
+	if err != nil {
+		log.Fatal(err)
+	}
+	kp, err := nkeys.FromRawSeed(preForType(keyType), rawSeed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s", seed)
+}
+
+func derive(key, path string) {
+	if key == "" {
+		log.Fatalf("Derive requires a master seed via -inkey <key>")
+	}
+	seed := readKey(key)
+	_, rawSeed, err := nkeys.DecodeSeed(seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	kp, err := hd.Derive(rawSeed, path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	childSeed, err := kp.Seed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s", childSeed)
+	pub, _ := kp.PublicKey()
+	log.Printf("%s", pub)
+}
+
+func readPassphrase(path string) []byte {
+	if path == "" {
+		log.Fatalf("This requires a passphrase via -passphrase <file>")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return bytes.TrimSpace(data)
+}
+
+func encryptSeed(key, passphraseFile string) {
+	if key == "" {
+		log.Fatalf("Encrypt requires a seed via -inkey <key>")
+	}
+	seed := readKey(key)
+	passphrase := readPassphrase(passphraseFile)
+	defer wipeSlice(passphrase)
+
+	armored, err := nkeys.EncryptSeed(seed, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(armored)
+}
+
+func decryptSeed(fname, passphraseFile string) {
+	armored, err := os.ReadFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	passphrase := readPassphrase(passphraseFile)
+	defer wipeSlice(passphrase)
+
+	seed, err := nkeys.DecryptSeed(armored, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer wipeSlice(seed)
+	log.Printf("%s", seed)
+}
+
+func sealMulti(fname, key, recipients string) {
+	if key == "" {
+		log.Fatalf("Seal requires an xkey seed via -inkey <key>")
+	}
+	if recipients == "" {
+		log.Fatalf("Seal-multi requires recipient xkey public keys via -recipient <xpub,xpub,...>")
+	}
+	seed := readKey(key)
+	kp, err := nkeys.FromSeed(seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	content, err := os.ReadFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sealed, err := kp.SealMulti(content, strings.Split(recipients, ","))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s", base64.StdEncoding.EncodeToString(sealed))
+}
+
 func preForType(keyType string) nkeys.PrefixByte {
 	keyType = strings.ToLower(keyType)
 	switch keyType {
@@ -193,8 +457,10 @@ func preForType(keyType string) nkeys.PrefixByte {
 		return nkeys.PrefixByteCluster
 	case "operator":
 		return nkeys.PrefixByteOperator
+	case "curve":
+		return nkeys.PrefixByteCurve
 	default:
-		log.Fatalf("Usage: nk -gen [user|account|server|cluster|operator]\n")
+		log.Fatalf("Usage: nk -gen [user|account|server|cluster|operator|curve]\n")
 	}
 	return nkeys.PrefixByte(0)
 }
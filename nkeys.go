@@ -0,0 +1,127 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nkeys is an Ed25519 based public-key signature system that
+// simplifies keys and seeds and performs signing and verification for
+// NATS servers and clients.
+package nkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+)
+
+// Version is the current version of the nkeys package.
+const Version = "0.3.0"
+
+// KeyPair provides the central interface to nkeys. A KeyPair is always
+// created from a PrefixByte and either holds a seed (and so can sign, or
+// for an xkey, Seal and Open) or only a public key (and so can only verify).
+type KeyPair interface {
+	// Seed returns the encoded seed.
+	Seed() ([]byte, error)
+
+	// PublicKey returns the encoded public key associated with the KeyPair.
+	PublicKey() (string, error)
+
+	// PrivateKey returns the raw, unencoded private key bytes.
+	PrivateKey() ([]byte, error)
+
+	// Sign signs the input and returns the raw signature.
+	Sign(input []byte) ([]byte, error)
+
+	// Verify verifies the signature against the given input.
+	Verify(input []byte, sig []byte) error
+
+	// Seal encrypts the input for recipient, who must hold the xkey seed
+	// matching recipientPub. Only xkey (curve) KeyPairs support this;
+	// any other KeyPair returns ErrIncompatibleKey.
+	Seal(input []byte, recipientPub string) ([]byte, error)
+
+	// Open decrypts input that was produced by the holder of senderPub's
+	// Seal call to this KeyPair's public key. Only xkey (curve) KeyPairs
+	// support this; any other KeyPair returns ErrIncompatibleKey.
+	Open(input []byte, senderPub string) ([]byte, error)
+
+	// SealMulti encrypts plaintext once and wraps the content-encryption
+	// key for each of recipients, producing a single envelope any one of
+	// them can Open. Only xkey (curve) KeyPairs support this; any other
+	// KeyPair returns ErrIncompatibleKey.
+	SealMulti(plaintext []byte, recipients []string) ([]byte, error)
+
+	// Wipe overwrites any sensitive material held by the KeyPair.
+	Wipe()
+}
+
+// CreatePair creates a new KeyPair of the requested type using crypto/rand
+// as the source of entropy.
+func CreatePair(prefix PrefixByte) (KeyPair, error) {
+	var rawSeed [32]byte
+	if _, err := rand.Read(rawSeed[:]); err != nil {
+		return nil, err
+	}
+	return FromRawSeed(prefix, rawSeed[:])
+}
+
+// FromRawSeed creates a KeyPair from the given 32-byte raw seed and prefix
+// byte, encoding it into the standard nkeys seed string form. A
+// PrefixByteCurve prefix yields an xkey KeyPair usable with Seal/Open; any
+// other prefix yields an ed25519 signing KeyPair.
+func FromRawSeed(prefix PrefixByte, rawSeed []byte) (KeyPair, error) {
+	if len(rawSeed) != ed25519.SeedSize {
+		return nil, ErrInvalidSeedLen
+	}
+	if !isValidPublicPrefix(prefix) {
+		return nil, ErrInvalidPrefixByte
+	}
+	seed, err := encodeSeed(prefix, rawSeed)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == PrefixByteCurve {
+		return &xkp{seed: []byte(seed)}, nil
+	}
+	return &kp{seed: []byte(seed)}, nil
+}
+
+// FromSeed creates a KeyPair from an encoded seed string. Seeds produced
+// for the xkey (curve) prefix yield a KeyPair usable with Seal/Open;
+// all others yield a signing KeyPair.
+func FromSeed(seed []byte) (KeyPair, error) {
+	prefix, _, err := decodeSeed(string(seed))
+	if err != nil {
+		return nil, err
+	}
+	cp := make([]byte, len(seed))
+	copy(cp, seed)
+	if prefix == PrefixByteCurve {
+		return &xkp{seed: cp}, nil
+	}
+	return &kp{seed: cp}, nil
+}
+
+// FromPublicKey creates a KeyPair capable only of verification from an
+// encoded public key string.
+func FromPublicKey(public string) (KeyPair, error) {
+	prefix, raw, err := decode(public)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == PrefixByteCurve {
+		return nil, ErrIncompatibleKey
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+	return &pub{pre: prefix, pub: raw}, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import "crypto/ed25519"
+
+// pub is a KeyPair backed only by a public key, so it can verify but never
+// sign.
+type pub struct {
+	pre PrefixByte
+	pub []byte
+}
+
+// Seed is not available for a public-key-only KeyPair.
+func (p *pub) Seed() ([]byte, error) {
+	return nil, ErrPublicKeyOnly
+}
+
+// PublicKey returns the encoded public key.
+func (p *pub) PublicKey() (string, error) {
+	return encode(p.pre, p.pub)
+}
+
+// PrivateKey is not available for a public-key-only KeyPair.
+func (p *pub) PrivateKey() ([]byte, error) {
+	return nil, ErrPublicKeyOnly
+}
+
+// Sign is not available for a public-key-only KeyPair.
+func (p *pub) Sign(input []byte) ([]byte, error) {
+	return nil, ErrCannotSign
+}
+
+// Verify verifies the signature against the input using the held public key.
+func (p *pub) Verify(input []byte, sig []byte) error {
+	if !ed25519.Verify(ed25519.PublicKey(p.pub), input, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Seal is not available for an ed25519 public key.
+func (p *pub) Seal(input []byte, recipientPub string) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// Open is not available for an ed25519 public key.
+func (p *pub) Open(input []byte, senderPub string) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// SealMulti is not available for an ed25519 public key.
+func (p *pub) SealMulti(plaintext []byte, recipients []string) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// Wipe zeroes out the in-memory copy of the public key.
+func (p *pub) Wipe() {
+	for i := range p.pub {
+		p.pub[i] = 0
+	}
+}
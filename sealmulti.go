@@ -0,0 +1,163 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// sealMultiMagic identifies a SealMulti envelope so Open can tell it apart
+// from a plain single-recipient Seal payload.
+var sealMultiMagic = [3]byte{'N', 'K', 'M'}
+
+const sealMultiVersion = 1
+
+// wrappedKeyLen is the size of a content-encryption key (32 bytes) once
+// sealed with NaCl box (which appends box.Overhead bytes of MAC).
+const wrappedKeyLen = 32 + box.Overhead
+
+// sealMultiEntryLen is the size of one {recipient-pub, nonce, wrapped-CEK}
+// entry in a SealMulti envelope.
+const sealMultiEntryLen = 32 + 24 + wrappedKeyLen
+
+// SealMulti encrypts plaintext once with XChaCha20-Poly1305 under a random
+// content-encryption key (CEK), then wraps that CEK once per recipient
+// using NaCl box between this KeyPair's seed and each recipient's xkey
+// public key. The resulting self-describing envelope is
+// magic || version || recipient count || entries... || ciphertext nonce ||
+// ciphertext (with Poly1305 tag). Any recipient can call Open to recover
+// the CEK from their own entry and decrypt the payload; every entry wraps
+// the same CEK, each under a different recipient's key.
+func (pair *xkp) SealMulti(plaintext []byte, recipients []string) ([]byte, error) {
+	priv, _, err := pair.rawKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var cek [32]byte
+	if _, err := rand.Read(cek[:]); err != nil {
+		return nil, err
+	}
+	defer wipe(cek[:])
+
+	aead, err := chacha20poly1305.NewX(cek[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var ctNonce [24]byte
+	if _, err := rand.Read(ctNonce[:]); err != nil {
+		return nil, err
+	}
+	ciphertext := aead.Seal(nil, ctNonce[:], plaintext, nil)
+
+	var buf bytes.Buffer
+	buf.Write(sealMultiMagic[:])
+	buf.WriteByte(sealMultiVersion)
+
+	var countBuf [2]byte
+	binary.BigEndian.PutUint16(countBuf[:], uint16(len(recipients)))
+	buf.Write(countBuf[:])
+
+	for _, r := range recipients {
+		rpub, err := recipientPublic(r)
+		if err != nil {
+			return nil, err
+		}
+		var nonce [24]byte
+		if _, err := rand.Read(nonce[:]); err != nil {
+			return nil, err
+		}
+		wrapped := box.Seal(nil, cek[:], &nonce, rpub, &priv)
+
+		buf.Write(rpub[:])
+		buf.Write(nonce[:])
+		buf.Write(wrapped)
+	}
+
+	buf.Write(ctNonce[:])
+	buf.Write(ciphertext)
+
+	return buf.Bytes(), nil
+}
+
+// isSealMultiEnvelope reports whether data looks like a SealMulti envelope.
+func isSealMultiEnvelope(data []byte) bool {
+	return len(data) >= 4 && bytes.Equal(data[:3], sealMultiMagic[:]) && data[3] == sealMultiVersion
+}
+
+// openMulti scans a SealMulti envelope for the wrapped CEK addressed to
+// this KeyPair's own public key, unwraps it using senderPub, and decrypts
+// the payload.
+func (pair *xkp) openMulti(data []byte, senderPub *[32]byte) ([]byte, error) {
+	priv, pub, err := pair.rawKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	body := data[4:]
+	if len(body) < 2 {
+		return nil, ErrInvalidKey
+	}
+	count := int(binary.BigEndian.Uint16(body[:2]))
+	body = body[2:]
+
+	if len(body) < count*sealMultiEntryLen+24 {
+		return nil, ErrInvalidKey
+	}
+
+	var cek *[32]byte
+	for i := 0; i < count; i++ {
+		entry := body[i*sealMultiEntryLen : (i+1)*sealMultiEntryLen]
+		if !bytes.Equal(entry[:32], pub[:]) {
+			continue
+		}
+		var nonce [24]byte
+		copy(nonce[:], entry[32:56])
+		wrapped := entry[56:sealMultiEntryLen]
+
+		raw, ok := box.Open(nil, wrapped, &nonce, senderPub, &priv)
+		if !ok {
+			return nil, ErrDecryptFailed
+		}
+		var k [32]byte
+		copy(k[:], raw)
+		cek = &k
+		break
+	}
+	if cek == nil {
+		return nil, ErrDecryptFailed
+	}
+	defer wipe(cek[:])
+
+	rest := body[count*sealMultiEntryLen:]
+	var ctNonce [24]byte
+	copy(ctNonce[:], rest[:24])
+	ciphertext := rest[24:]
+
+	aead, err := chacha20poly1305.NewX(cek[:])
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, ctNonce[:], ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2018-2019 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nkeys
+
+import (
+	"crypto/rand"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// xkp is an X25519 (Curve25519) KeyPair used for Seal/Open (NaCl box
+// authenticated encryption), as opposed to the ed25519 KeyPairs used for
+// signing.
+type xkp struct {
+	seed []byte
+}
+
+// CreateCurveKeyPair creates a new xkey (Curve25519) KeyPair using
+// crypto/rand as the source of entropy.
+func CreateCurveKeyPair() (KeyPair, error) {
+	var rawSeed [32]byte
+	if _, err := rand.Read(rawSeed[:]); err != nil {
+		return nil, err
+	}
+	return FromCurveSeed(rawSeed[:])
+}
+
+// FromCurveSeed creates an xkey KeyPair from a 32-byte raw X25519 seed.
+func FromCurveSeed(rawSeed []byte) (KeyPair, error) {
+	if len(rawSeed) != 32 {
+		return nil, ErrInvalidSeedLen
+	}
+	seed, err := encodeSeed(PrefixByteCurve, rawSeed)
+	if err != nil {
+		return nil, err
+	}
+	return &xkp{seed: []byte(seed)}, nil
+}
+
+// rawKeys returns the raw 32-byte private scalar and its derived public key.
+func (pair *xkp) rawKeys() (priv, pub [32]byte, err error) {
+	prefix, raw, derr := decodeSeed(string(pair.seed))
+	if derr != nil {
+		return priv, pub, derr
+	}
+	if prefix != PrefixByteCurve {
+		return priv, pub, ErrIncompatibleKey
+	}
+	copy(priv[:], raw)
+	curve25519.ScalarBaseMult(&pub, &priv)
+	return priv, pub, nil
+}
+
+// Seed returns the encoded seed.
+func (pair *xkp) Seed() ([]byte, error) {
+	return pair.seed, nil
+}
+
+// PublicKey returns the encoded xkey public key.
+func (pair *xkp) PublicKey() (string, error) {
+	_, pub, err := pair.rawKeys()
+	if err != nil {
+		return "", err
+	}
+	return encode(PrefixByteCurve, pub[:])
+}
+
+// PrivateKey returns the raw 32-byte X25519 private scalar.
+func (pair *xkp) PrivateKey() ([]byte, error) {
+	priv, _, err := pair.rawKeys()
+	if err != nil {
+		return nil, err
+	}
+	cp := make([]byte, 32)
+	copy(cp, priv[:])
+	return cp, nil
+}
+
+// Sign is not available for an xkey KeyPair.
+func (pair *xkp) Sign(input []byte) ([]byte, error) {
+	return nil, ErrIncompatibleKey
+}
+
+// Verify is not available for an xkey KeyPair.
+func (pair *xkp) Verify(input []byte, sig []byte) error {
+	return ErrIncompatibleKey
+}
+
+// recipientPublic decodes an encoded xkey public key string into its raw
+// 32-byte form.
+func recipientPublic(encoded string) (*[32]byte, error) {
+	prefix, raw, err := decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != PrefixByteCurve || len(raw) != 32 {
+		return nil, ErrInvalidKey
+	}
+	var pub [32]byte
+	copy(pub[:], raw)
+	return &pub, nil
+}
+
+// Seal encrypts input for recipientPub using NaCl box: a random 24-byte
+// nonce is generated and prepended to the returned ciphertext so that Open
+// can recover it.
+func (pair *xkp) Seal(input []byte, recipientPub string) ([]byte, error) {
+	priv, _, err := pair.rawKeys()
+	if err != nil {
+		return nil, err
+	}
+	rpub, err := recipientPublic(recipientPub)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 24+len(input)+box.Overhead)
+	out = append(out, nonce[:]...)
+	return box.Seal(out, input, &nonce, rpub, &priv), nil
+}
+
+// Open reverses Seal, decrypting input that was sealed by the holder of
+// senderPub to this KeyPair's public key. It also recognizes and opens a
+// SealMulti envelope, recovering the CEK addressed to this KeyPair before
+// decrypting the payload.
+func (pair *xkp) Open(input []byte, senderPub string) ([]byte, error) {
+	spub, err := recipientPublic(senderPub)
+	if err != nil {
+		return nil, err
+	}
+	if isSealMultiEnvelope(input) {
+		return pair.openMulti(input, spub)
+	}
+
+	priv, _, err := pair.rawKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(input) < 24 {
+		return nil, ErrInvalidKey
+	}
+	var nonce [24]byte
+	copy(nonce[:], input[:24])
+	out, ok := box.Open(nil, input[24:], &nonce, spub, &priv)
+	if !ok {
+		return nil, ErrDecryptFailed
+	}
+	return out, nil
+}
+
+// Wipe overwrites the in-memory copy of the encoded seed with 'x' bytes.
+func (pair *xkp) Wipe() {
+	for i := range pair.seed {
+		pair.seed[i] = 'x'
+	}
+}